@@ -0,0 +1,181 @@
+// Package vastcaptest provides an in-memory httptest.Server that speaks the
+// same createTask/getTaskResult protocol as the vastcap API, so callers of
+// github.com/JustYuuto/vastcap-go can exercise their captcha-solving flows
+// without spending API credits or hitting the network.
+package vastcaptest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+// Result describes how the mock server should answer a single createTask
+// call once the task is retrieved via getTaskResult.
+type Result struct {
+	// Solution is sent verbatim as the "solution" object once the task is
+	// ready. Ignored if ErrorCode is set.
+	Solution map[string]interface{}
+	// ErrorCode, if set, makes the task resolve to status "failed" with
+	// this errorCode (see vastcap's Err* constants for well-known values).
+	ErrorCode        string
+	ErrorId          int
+	ErrorDescription string
+	// Delay is how long the task reports status "processing" before
+	// resolving to "ready" or "failed". Zero means it resolves immediately.
+	Delay time.Duration
+}
+
+// Server is a mock vastcap API. The zero value is not usable; construct one
+// with NewServer.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	queue    []Result
+	def      Result
+	hasDef   bool
+	nextID   int
+	tasks    map[string]taskState
+	lastTask map[string]interface{}
+	lastUA   string
+}
+
+type taskState struct {
+	result    Result
+	createdAt time.Time
+	getCalls  int
+}
+
+// NewServer starts a mock vastcap API listening on a local address. Callers
+// should Close it when done, and point a *vastcap.VastCap at it with
+// vastcap.WithBaseURL(srv.URL).
+func NewServer() *Server {
+	s := &Server{tasks: map[string]taskState{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/solver/createTask", s.handleCreateTask)
+	mux.HandleFunc("/api/solver/getTaskResult", s.handleGetTaskResult)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// QueueResult programs the outcome of the next createTask call. Results are
+// consumed in FIFO order; once the queue is empty, SetDefaultResult's value
+// is used for every subsequent task.
+func (s *Server) QueueResult(r Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = append(s.queue, r)
+}
+
+// SetDefaultResult sets the outcome used for any task with no queued Result.
+func (s *Server) SetDefaultResult(r Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.def = r
+	s.hasDef = true
+}
+
+// LastTask returns the "task" object of the most recently received
+// createTask call, for asserting on the type/proxy/etc. a client sent.
+func (s *Server) LastTask() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastTask
+}
+
+// LastUserAgent returns the User-Agent header of the most recently received
+// createTask request, if any. getTaskResult requests do not update it.
+func (s *Server) LastUserAgent() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastUA
+}
+
+// GetResultCallCount returns how many times getTaskResult has been called
+// for taskID, for asserting polling behavior (e.g. attempt limits).
+func (s *Server) GetResultCallCount(taskID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tasks[taskID].getCalls
+}
+
+func (s *Server) handleCreateTask(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		ClientKey string                 `json:"clientKey"`
+		Task      map[string]interface{} `json:"task"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	s.mu.Lock()
+	var result Result
+	if len(s.queue) > 0 {
+		result, s.queue = s.queue[0], s.queue[1:]
+	} else if s.hasDef {
+		result = s.def
+	}
+	s.nextID++
+	taskID := fmt.Sprintf("task-%d", s.nextID)
+	s.tasks[taskID] = taskState{result: result, createdAt: time.Now()}
+	s.lastTask = body.Task
+	s.lastUA = r.Header.Get("User-Agent")
+	s.mu.Unlock()
+
+	writeJSON(w, map[string]interface{}{"taskId": taskID})
+}
+
+func (s *Server) handleGetTaskResult(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		TaskID string `json:"taskId"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	s.mu.Lock()
+	state, ok := s.tasks[body.TaskID]
+	if ok {
+		state.getCalls++
+		s.tasks[body.TaskID] = state
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeJSON(w, map[string]interface{}{
+			"status": "failed",
+			"error": map[string]interface{}{
+				"errorId":          1,
+				"errorCode":        "ERROR_NO_SUCH_CAPCHA_ID",
+				"errorDescription": "no task with this ID was found",
+			},
+		})
+		return
+	}
+
+	if time.Since(state.createdAt) < state.result.Delay {
+		writeJSON(w, map[string]interface{}{"status": "processing"})
+		return
+	}
+
+	if state.result.ErrorCode != "" {
+		writeJSON(w, map[string]interface{}{
+			"status": "failed",
+			"error": map[string]interface{}{
+				"errorId":          state.result.ErrorId,
+				"errorCode":        state.result.ErrorCode,
+				"errorDescription": state.result.ErrorDescription,
+			},
+		})
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"status":   "ready",
+		"solution": state.result.Solution,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}